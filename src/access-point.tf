@@ -0,0 +1,63 @@
+locals {
+  access_point_enabled = local.enabled && var.access_point_enabled
+}
+
+module "access_point_label" {
+  source  = "cloudposse/label/null"
+  version = "0.25.0"
+
+  # Access point names are limited to 50 characters
+  id_length_limit = 50
+  attributes      = ["read"]
+
+  context = module.this.context
+}
+
+# Lets read-side consumers (e.g. analytics tools) read Config snapshots through their own policy,
+# without loosening the main bucket policy.
+resource "aws_s3_access_point" "default" {
+  count = local.access_point_enabled ? 1 : 0
+
+  bucket = module.config_bucket.bucket_id
+  name   = module.access_point_label.id
+
+  public_access_block_configuration {
+    block_public_acls       = true
+    block_public_policy     = true
+    ignore_public_acls      = true
+    restrict_public_buckets = true
+  }
+
+  lifecycle {
+    # The policy is managed by `aws_s3control_access_point_policy` below
+    ignore_changes = [policy]
+
+    precondition {
+      condition     = length(var.access_point_principal_arns) > 0
+      error_message = "The access_point_principal_arns must not be empty when access_point_enabled is true."
+    }
+  }
+}
+
+data "aws_iam_policy_document" "access_point" {
+  count = local.access_point_enabled ? 1 : 0
+
+  statement {
+    sid       = "AllowReadOnlyAccess"
+    effect    = "Allow"
+    actions   = ["s3:GetObject", "s3:ListBucket"]
+    resources = [aws_s3_access_point.default[0].arn, format("%s/object/*", aws_s3_access_point.default[0].arn)]
+
+    principals {
+      type        = "AWS"
+      identifiers = var.access_point_principal_arns
+    }
+  }
+}
+
+resource "aws_s3control_access_point_policy" "default" {
+  count = local.access_point_enabled ? 1 : 0
+
+  access_point_arn = aws_s3_access_point.default[0].arn
+  policy           = data.aws_iam_policy_document.access_point[0].json
+}
@@ -0,0 +1,83 @@
+locals {
+  cloudtrail_data_events_enabled = local.enabled && var.cloudtrail_data_events_enabled
+}
+
+module "cloudtrail_label" {
+  source  = "cloudposse/label/null"
+  version = "0.25.0"
+
+  attributes = ["data-events"]
+
+  context = module.this.context
+}
+
+# Audits object-level reads and deletes of Config data.
+# Event selectors cannot be added to a trail managed elsewhere, so a dedicated trail is created
+# that delivers to an existing CloudTrail bucket.
+resource "aws_cloudtrail" "data_events" {
+  count = local.cloudtrail_data_events_enabled ? 1 : 0
+
+  name                          = module.cloudtrail_label.id
+  s3_bucket_name                = var.cloudtrail_bucket_name
+  enable_log_file_validation    = true
+  include_global_service_events = false
+  is_multi_region_trail         = false
+
+  # Reads and deletes only, so that the trail does not record every Config delivery
+  advanced_event_selector {
+    name = "Config bucket object reads"
+
+    field_selector {
+      field  = "eventCategory"
+      equals = ["Data"]
+    }
+
+    field_selector {
+      field  = "resources.type"
+      equals = ["AWS::S3::Object"]
+    }
+
+    field_selector {
+      field       = "resources.ARN"
+      starts_with = [format("%s/", module.config_bucket.bucket_arn)]
+    }
+
+    field_selector {
+      field  = "readOnly"
+      equals = ["true"]
+    }
+  }
+
+  advanced_event_selector {
+    name = "Config bucket object deletes"
+
+    field_selector {
+      field  = "eventCategory"
+      equals = ["Data"]
+    }
+
+    field_selector {
+      field  = "resources.type"
+      equals = ["AWS::S3::Object"]
+    }
+
+    field_selector {
+      field       = "resources.ARN"
+      starts_with = [format("%s/", module.config_bucket.bucket_arn)]
+    }
+
+    field_selector {
+      field  = "eventName"
+      equals = ["DeleteObject", "DeleteObjects"]
+    }
+  }
+
+  lifecycle {
+    precondition {
+      condition     = var.cloudtrail_bucket_name != null
+      error_message = "The cloudtrail_bucket_name must be set when cloudtrail_data_events_enabled is true."
+    }
+  }
+
+  tags = module.cloudtrail_label.tags
+}
@@ -0,0 +1,11 @@
+# Lets security teams query configuration history with Athena directly
+module "glue_catalog" {
+  source = "./modules/glue-catalog"
+
+  bucket_id     = module.config_bucket.bucket_id
+  s3_key_prefix = var.config_delivery_s3_key_prefix
+  regions       = length(var.glue_catalog_regions) > 0 ? var.glue_catalog_regions : [var.region]
+
+  enabled = local.enabled && var.glue_catalog_enabled
+  context = module.this.context
+}
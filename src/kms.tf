@@ -0,0 +1,24 @@
+# Key policy statements allowing AWS Config to encrypt deliveries with the customer managed key.
+# Missing key grants are the most common cause of failing multi-account delivery to a centralized bucket.
+# See https://docs.aws.amazon.com/config/latest/developerguide/s3-kms-key-policy.html
+data "aws_iam_policy_document" "kms_key" {
+  count = local.enabled && local.sse_kms_enabled ? 1 : 0
+
+  statement {
+    sid       = "AWSConfigKMSPolicy"
+    effect    = "Allow"
+    actions   = ["kms:Decrypt", "kms:GenerateDataKey"]
+    resources = ["*"]
+
+    principals {
+      type        = "Service"
+      identifiers = ["config.amazonaws.com"]
+    }
+
+    condition {
+      test     = "StringEquals"
+      variable = var.organization_id != null ? "aws:SourceOrgID" : "aws:SourceAccount"
+      values   = var.organization_id != null ? [var.organization_id] : local.config_delivery_account_ids
+    }
+  }
+}
@@ -0,0 +1,27 @@
+locals {
+  macie_classification_job_enabled = local.enabled && var.macie_classification_job_enabled
+}
+
+# Lets compliance teams prove that Config data does not contain unexpected sensitive content.
+# Amazon Macie must already be enabled in the account.
+resource "aws_macie2_classification_job" "default" {
+  count = local.macie_classification_job_enabled ? 1 : 0
+
+  name     = module.this.id
+  job_type = "SCHEDULED"
+
+  schedule_frequency {
+    daily_schedule   = var.macie_classification_job_frequency == "DAILY" ? true : null
+    weekly_schedule  = var.macie_classification_job_frequency == "WEEKLY" ? "MONDAY" : null
+    monthly_schedule = var.macie_classification_job_frequency == "MONTHLY" ? 1 : null
+  }
+
+  s3_job_definition {
+    bucket_definitions {
+      account_id = data.aws_caller_identity.current.account_id
+      buckets    = [module.config_bucket.bucket_id]
+    }
+  }
+
+  tags = module.this.tags
+}
@@ -1,16 +1,719 @@
+locals {
+  enabled = module.this.enabled
+
+  bucket_name = var.existing_bucket_name != null ? var.existing_bucket_name : (
+    var.bucket_name_override != null ? var.bucket_name_override : module.this.id
+  )
+  partition  = var.partition != null ? var.partition : data.aws_partition.current.partition
+  bucket_arn = format("arn:%s:s3:::%s", local.partition, local.bucket_name)
+
+  # The prefix AWS Config delivers under, normalized to either "" or "<prefix>/"
+  config_delivery_s3_key_prefix = var.config_delivery_s3_key_prefix != "" ? format("%s/", trim(var.config_delivery_s3_key_prefix, "/")) : ""
+
+  # The accounts whose AWS Config may use the KMS key and the SNS topic, unless the whole organization may
+  config_delivery_account_ids = length(var.config_delivery_account_ids) > 0 ? var.config_delivery_account_ids : [data.aws_caller_identity.current.account_id]
+
+  # Without an organization or an explicit list of accounts, the bucket stays open to AWS Config in any account,
+  # as with the `cloudposse/config-storage/aws` module this component used before
+  config_delivery_source_restricted = var.organization_id != null || length(var.config_delivery_account_ids) > 0
+
+  # AWS Config delivers under `<prefix>/AWSLogs/<account>/Config/`
+  config_delivery_resources = var.organization_id != null ? [
+    format("%s/%sAWSLogs/*/Config/*", local.bucket_arn, local.config_delivery_s3_key_prefix)
+    ] : length(var.config_delivery_account_ids) > 0 ? [
+    for account_id in var.config_delivery_account_ids : format("%s/%sAWSLogs/%s/Config/*", local.bucket_arn, local.config_delivery_s3_key_prefix, account_id)
+  ] : [format("%s/%sAWSLogs/*", local.bucket_arn, local.config_delivery_s3_key_prefix)]
+
+  sse_kms_enabled = contains(["aws:kms", "aws:kms:dsse"], var.sse_algorithm)
+
+  # S3 Bucket Keys are only supported with single-layer SSE-KMS
+  bucket_key_enabled = var.sse_algorithm == "aws:kms" && var.bucket_key_enabled
+
+  # The conformance packs service-linked role, in any account of the organization or in this account only
+  config_conforms_role_arn = format(
+    "arn:%s:iam::%s:role/aws-service-role/config-conforms.amazonaws.com/AWSServiceRoleForConfigConforms",
+    local.partition,
+    var.organization_id != null ? "*" : data.aws_caller_identity.current.account_id,
+  )
+
+  aggregator_principal_arns = concat(
+    var.aggregator_account_id != null ? [format("arn:%s:iam::%s:root", local.partition, var.aggregator_account_id)] : [],
+    var.aggregator_role_arns,
+  )
+  aggregator_access_enabled = local.enabled && length(local.aggregator_principal_arns) > 0
+
+  # Map of principal ARN => list of allowed key prefixes (empty list means the whole bucket)
+  privileged_principal_arns = merge(var.privileged_principal_arns...)
+
+  allowed_sources_enabled = local.enabled && length(concat(var.allowed_source_cidrs, var.allowed_source_vpce_ids, var.allowed_source_vpc_ids)) > 0
+
+  # Roles acting on the bucket from outside the allowed networks, which must not be locked out:
+  # the role applying this component, and the roles AWS services assume on its behalf
+  allowed_sources_exempt_principal_arns = compact(concat(
+    [module.iam_roles.terraform_role_arn],
+    var.s3_replication_enabled ? [module.config_bucket.replication_role_arn] : [],
+    local.macie_classification_job_enabled ? [
+      format("arn:%s:iam::%s:role/aws-service-role/macie.amazonaws.com/AWSServiceRoleForAmazonMacie", local.partition, data.aws_caller_identity.current.account_id)
+    ] : [],
+    var.allowed_source_exempt_principal_arns,
+  ))
+
+  source_policy_documents = concat(
+    data.aws_iam_policy_document.ssl_requests_only[*].json,
+    data.aws_iam_policy_document.config_bucket[*].json,
+    data.aws_iam_policy_document.conformance_pack[*].json,
+    data.aws_iam_policy_document.aggregator[*].json,
+    data.aws_iam_policy_document.read_only_roles[*].json,
+    data.aws_iam_policy_document.privileged_principals[*].json,
+    data.aws_iam_policy_document.deny_object_deletion[*].json,
+    data.aws_iam_policy_document.allowed_sources[*].json,
+  )
+
+  access_log_bucket_name = module.logs_bucket.outputs.bucket_id
+
+  logging = local.access_log_bucket_name != "" ? [
+    {
+      bucket_name = local.access_log_bucket_name
+      prefix      = format("logs/%s/", local.bucket_name)
+    }
+  ] : []
+
+  # Replication Time Control requires replication metrics to be enabled as well
+  replication_metrics_enabled = var.replication_metrics_enabled || var.replication_time_control_enabled
+
+  s3_replication_rules = [
+    {
+      id     = "replication"
+      status = "Enabled"
+
+      delete_marker_replication = {
+        status = "Enabled"
+      }
+
+      # With SSE-KMS, S3 silently skips objects that are not selected here and given a replica key below
+      source_selection_criteria = local.sse_kms_enabled ? {
+        sse_kms_encrypted_objects = {
+          enabled = true
+        }
+      } : null
+
+      destination = {
+        bucket             = var.s3_replica_bucket_arn
+        replica_kms_key_id = local.sse_kms_enabled ? var.s3_replica_kms_key_arn : null
+        metrics = {
+          status = local.replication_metrics_enabled ? "Enabled" : "Disabled"
+        }
+        replication_time = {
+          status = var.replication_time_control_enabled ? "Enabled" : "Disabled"
+        }
+      }
+    }
+  ]
+
+  # The bucket-wide rule, followed by any per-prefix rules (e.g. a different retention for production accounts)
+  lifecycle_rule_settings = merge(
+    {
+      (local.bucket_name) = {
+        enabled                            = var.lifecycle_rule_enabled
+        prefix                             = var.lifecycle_prefix != "" ? var.lifecycle_prefix : null
+        standard_transition_days           = var.standard_transition_days
+        glacier_transition_days            = var.glacier_transition_days
+        expiration_days                    = var.expiration_days
+        noncurrent_version_transition_days = var.noncurrent_version_transition_days
+        noncurrent_version_expiration_days = var.noncurrent_version_expiration_days
+      }
+    },
+    {
+      for prefix, rule in var.prefix_lifecycle_rules : prefix => {
+        enabled                            = true
+        prefix                             = prefix
+        standard_transition_days           = coalesce(rule.standard_transition_days, var.standard_transition_days)
+        glacier_transition_days            = coalesce(rule.glacier_transition_days, var.glacier_transition_days)
+        expiration_days                    = coalesce(rule.expiration_days, var.expiration_days)
+        noncurrent_version_transition_days = coalesce(rule.noncurrent_version_transition_days, var.noncurrent_version_transition_days)
+        noncurrent_version_expiration_days = coalesce(rule.noncurrent_version_expiration_days, var.noncurrent_version_expiration_days)
+      }
+    }
+  )
+
+  lifecycle_configuration_rules = concat([
+    for id, rule in local.lifecycle_rule_settings : {
+      enabled = rule.enabled
+      id      = id
+
+      abort_incomplete_multipart_upload_days = 5
+
+      filter_and = rule.prefix != null ? { prefix = rule.prefix } : null
+
+      transition = concat(
+        [{ days = rule.standard_transition_days, storage_class = "STANDARD_IA" }],
+        var.enable_glacier_transition ? [{ days = rule.glacier_transition_days, storage_class = "GLACIER" }] : [],
+      )
+      noncurrent_version_transition = var.enable_glacier_transition ? [
+        { noncurrent_days = rule.noncurrent_version_transition_days, storage_class = "GLACIER" }
+      ] : []
+      noncurrent_version_expiration = {
+        newer_noncurrent_versions = var.newer_noncurrent_versions
+        noncurrent_days           = rule.noncurrent_version_expiration_days
+      }
+      expiration = {
+        days = rule.expiration_days
+      }
+    }
+    ], [
+    # Expiration-only rules for objects carrying specific tags, e.g. `oversized = "true"`
+    for rule in var.tag_lifecycle_rules : {
+      enabled = true
+      id      = rule.id
+
+      filter_and = {
+        prefix = var.lifecycle_prefix != "" ? var.lifecycle_prefix : null
+        tags   = rule.tags
+      }
+
+      noncurrent_version_expiration = {
+        noncurrent_days = rule.noncurrent_version_expiration_days != null ? rule.noncurrent_version_expiration_days : rule.expiration_days
+      }
+      expiration = {
+        days = rule.expiration_days
+      }
+    }
+  ])
+}
+
+data "aws_partition" "current" {}
+
+data "aws_caller_identity" "current" {}
+
+# The IAM role (or user) behind the current session, which must keep managing the bucket
+data "aws_iam_session_context" "current" {
+  count = local.enabled && var.deny_object_deletion_enabled ? 1 : 0
+
+  arn = data.aws_caller_identity.current.arn
+}
+
+# The TLS-only statement `cloudposse/s3-bucket/aws` would otherwise add, rendered here so that the
+# `config_bucket_policy` output matches the policy applied to the bucket
+data "aws_iam_policy_document" "ssl_requests_only" {
+  count = local.enabled ? 1 : 0
+
+  statement {
+    sid       = "ForceSSLOnlyAccess"
+    effect    = "Deny"
+    actions   = ["s3:*"]
+    resources = [local.bucket_arn, format("%s/*", local.bucket_arn)]
+
+    principals {
+      type        = "*"
+      identifiers = ["*"]
+    }
+
+    condition {
+      test     = "Bool"
+      variable = "aws:SecureTransport"
+      values   = ["false"]
+    }
+  }
+}
+
+# The policy AWS Config needs to deliver configuration snapshots and history files.
+# With `organization_id` or `config_delivery_account_ids` set, source conditions keep AWS Config in other accounts
+# from using the bucket (the confused deputy problem).
+# See https://docs.aws.amazon.com/config/latest/developerguide/s3-bucket-policy.html
+data "aws_iam_policy_document" "config_bucket" {
+  count = local.enabled ? 1 : 0
+
+  statement {
+    sid       = "AWSConfigBucketPermissionsCheck"
+    effect    = "Allow"
+    actions   = ["s3:GetBucketAcl"]
+    resources = [local.bucket_arn]
+
+    principals {
+      type        = "Service"
+      identifiers = ["config.amazonaws.com"]
+    }
+
+    dynamic "condition" {
+      for_each = local.config_delivery_source_restricted ? [true] : []
+
+      content {
+        test     = "StringEquals"
+        variable = var.organization_id != null ? "aws:SourceOrgID" : "aws:SourceAccount"
+        values   = var.organization_id != null ? [var.organization_id] : var.config_delivery_account_ids
+      }
+    }
+  }
+
+  statement {
+    sid       = "AWSConfigBucketExistenceCheck"
+    effect    = "Allow"
+    actions   = ["s3:ListBucket"]
+    resources = [local.bucket_arn]
+
+    principals {
+      type        = "Service"
+      identifiers = ["config.amazonaws.com"]
+    }
+
+    dynamic "condition" {
+      for_each = local.config_delivery_source_restricted ? [true] : []
+
+      content {
+        test     = "StringEquals"
+        variable = var.organization_id != null ? "aws:SourceOrgID" : "aws:SourceAccount"
+        values   = var.organization_id != null ? [var.organization_id] : var.config_delivery_account_ids
+      }
+    }
+  }
+
+  statement {
+    sid       = "AWSConfigBucketDelivery"
+    effect    = "Allow"
+    actions   = ["s3:PutObject"]
+    resources = local.config_delivery_resources
+
+    principals {
+      type        = "Service"
+      identifiers = ["config.amazonaws.com"]
+    }
+
+    condition {
+      test     = "StringEquals"
+      variable = "s3:x-amz-acl"
+      values   = ["bucket-owner-full-control"]
+    }
+
+    dynamic "condition" {
+      for_each = local.config_delivery_source_restricted ? [true] : []
+
+      content {
+        test     = "StringEquals"
+        variable = var.organization_id != null ? "aws:SourceOrgID" : "aws:SourceAccount"
+        values   = var.organization_id != null ? [var.organization_id] : var.config_delivery_account_ids
+      }
+    }
+  }
+}
+
+# Conformance packs deliver under `<prefix>/AWSLogs/` using their service-linked role.
+# Organization conformance packs only accept delivery buckets whose name starts with `awsconfigconforms`.
+# See https://docs.aws.amazon.com/config/latest/developerguide/conformance-pack-organization-apis.html
+data "aws_iam_policy_document" "conformance_pack" {
+  count = local.enabled && var.conformance_pack_access_enabled ? 1 : 0
+
+  lifecycle {
+    precondition {
+      condition     = var.organization_id == null || startswith(local.bucket_name, "awsconfigconforms")
+      error_message = "The bucket name must start with `awsconfigconforms` when `conformance_pack_access_enabled` is true and `organization_id` is set. Set `bucket_name_override` accordingly."
+    }
+  }
+
+  statement {
+    sid       = "AWSConfigConformsBucketPermissionsCheck"
+    effect    = "Allow"
+    actions   = ["s3:GetBucketAcl"]
+    resources = [local.bucket_arn]
+
+    principals {
+      type        = "AWS"
+      identifiers = ["*"]
+    }
+
+    condition {
+      test     = "ArnLike"
+      variable = "aws:PrincipalArn"
+      values   = [local.config_conforms_role_arn]
+    }
+
+    dynamic "condition" {
+      for_each = var.organization_id != null ? [var.organization_id] : []
+
+      content {
+        test     = "StringEquals"
+        variable = "aws:PrincipalOrgID"
+        values   = [condition.value]
+      }
+    }
+  }
+
+  statement {
+    sid       = "AWSConfigConformsBucketDelivery"
+    effect    = "Allow"
+    actions   = ["s3:PutObject"]
+    resources = [format("%s/%sAWSLogs/*", local.bucket_arn, local.config_delivery_s3_key_prefix)]
+
+    principals {
+      type        = "AWS"
+      identifiers = ["*"]
+    }
+
+    condition {
+      test     = "StringEquals"
+      variable = "s3:x-amz-acl"
+      values   = ["bucket-owner-full-control"]
+    }
+
+    condition {
+      test     = "ArnLike"
+      variable = "aws:PrincipalArn"
+      values   = [local.config_conforms_role_arn]
+    }
+
+    dynamic "condition" {
+      for_each = var.organization_id != null ? [var.organization_id] : []
+
+      content {
+        test     = "StringEquals"
+        variable = "aws:PrincipalOrgID"
+        values   = [condition.value]
+      }
+    }
+  }
+
+  statement {
+    sid       = "AWSConfigConformsBucketReadAccess"
+    effect    = "Allow"
+    actions   = ["s3:GetObject"]
+    resources = [format("%s/%sAWSLogs/*", local.bucket_arn, local.config_delivery_s3_key_prefix)]
+
+    principals {
+      type        = "AWS"
+      identifiers = ["*"]
+    }
+
+    condition {
+      test     = "ArnLike"
+      variable = "aws:PrincipalArn"
+      values   = [local.config_conforms_role_arn]
+    }
+
+    dynamic "condition" {
+      for_each = var.organization_id != null ? [var.organization_id] : []
+
+      content {
+        test     = "StringEquals"
+        variable = "aws:PrincipalOrgID"
+        values   = [condition.value]
+      }
+    }
+  }
+}
+
+# Read-only access for an organization aggregator or security-tooling account consuming the delivered snapshots
+data "aws_iam_policy_document" "aggregator" {
+  count = local.aggregator_access_enabled ? 1 : 0
+
+  statement {
+    sid       = "AggregatorListBucket"
+    effect    = "Allow"
+    actions   = ["s3:ListBucket"]
+    resources = [local.bucket_arn]
+
+    principals {
+      type        = "AWS"
+      identifiers = local.aggregator_principal_arns
+    }
+  }
+
+  statement {
+    sid       = "AggregatorGetObject"
+    effect    = "Allow"
+    actions   = ["s3:GetObject"]
+    resources = [format("%s/*", local.bucket_arn)]
+
+    principals {
+      type        = "AWS"
+      identifiers = local.aggregator_principal_arns
+    }
+  }
+}
+
+# Read-only access for specific IAM roles, e.g. auditors
+data "aws_iam_policy_document" "read_only_roles" {
+  count = local.enabled && length(var.read_only_role_arns) > 0 ? 1 : 0
+
+  statement {
+    sid       = "ReadOnlyRolesListBucket"
+    effect    = "Allow"
+    actions   = ["s3:ListBucket"]
+    resources = [local.bucket_arn]
+
+    principals {
+      type        = "AWS"
+      identifiers = var.read_only_role_arns
+    }
+  }
+
+  statement {
+    sid       = "ReadOnlyRolesGetObject"
+    effect    = "Allow"
+    actions   = ["s3:GetObject"]
+    resources = [format("%s/*", local.bucket_arn)]
+
+    principals {
+      type        = "AWS"
+      identifiers = var.read_only_role_arns
+    }
+  }
+}
+
+# Narrowly scoped access for privileged principals such as incident-response roles.
+# Rendered here rather than by `cloudposse/s3-bucket/aws`, so that the `config_bucket_policy` output is complete.
+data "aws_iam_policy_document" "privileged_principals" {
+  count = local.enabled && length(local.privileged_principal_arns) > 0 ? 1 : 0
+
+  dynamic "statement" {
+    for_each = keys(local.privileged_principal_arns)
+
+    content {
+      sid     = format("AllowPrivilegedPrincipal%d", statement.key)
+      effect  = "Allow"
+      actions = var.privileged_principal_actions
+      resources = concat(
+        [local.bucket_arn],
+        length(local.privileged_principal_arns[statement.value]) == 0 ? [format("%s/*", local.bucket_arn)] : [
+          for prefix in local.privileged_principal_arns[statement.value] : format("%s/%s*", local.bucket_arn, prefix)
+        ],
+      )
+
+      principals {
+        type        = "AWS"
+        identifiers = [statement.value]
+      }
+    }
+  }
+}
+
+# Config history is only ever removed by lifecycle expiration, which is not subject to the bucket policy.
+# Changes that would lift the deny or expire the history early are reserved for the break-glass roles
+# and for the principal applying this component.
+data "aws_iam_policy_document" "deny_object_deletion" {
+  count = local.enabled && var.deny_object_deletion_enabled ? 1 : 0
+
+  statement {
+    sid       = "DenyObjectDeletion"
+    effect    = "Deny"
+    actions   = ["s3:DeleteObject", "s3:DeleteObjectVersion"]
+    resources = [format("%s/*", local.bucket_arn)]
+
+    principals {
+      type        = "AWS"
+      identifiers = ["*"]
+    }
+
+    dynamic "condition" {
+      for_each = length(var.break_glass_role_arns) > 0 ? [var.break_glass_role_arns] : []
+
+      content {
+        test     = "ArnNotLike"
+        variable = "aws:PrincipalArn"
+        values   = condition.value
+      }
+    }
+  }
+
+  statement {
+    sid       = "DenyDeletionProtectionChanges"
+    effect    = "Deny"
+    actions   = ["s3:PutBucketPolicy", "s3:DeleteBucketPolicy", "s3:PutLifecycleConfiguration", "s3:PutBucketVersioning"]
+    resources = [local.bucket_arn]
+
+    principals {
+      type        = "AWS"
+      identifiers = ["*"]
+    }
+
+    condition {
+      test     = "ArnNotLike"
+      variable = "aws:PrincipalArn"
+      values   = concat(var.break_glass_role_arns, data.aws_iam_session_context.current[*].issuer_arn)
+    }
+  }
+}
+
+# Data perimeter: deny access from outside the allowed networks.
+# Requests through a VPC endpoint carry no source IP, so they are only allowed by their endpoint or VPC.
+data "aws_iam_policy_document" "allowed_sources" {
+  count = local.allowed_sources_enabled ? 1 : 0
+
+  statement {
+    sid       = "DenyAccessFromOutsideAllowedSources"
+    effect    = "Deny"
+    actions   = ["s3:*"]
+    resources = [local.bucket_arn, format("%s/*", local.bucket_arn)]
+
+    principals {
+      type        = "AWS"
+      identifiers = ["*"]
+    }
+
+    dynamic "condition" {
+      for_each = length(var.allowed_source_cidrs) > 0 ? [var.allowed_source_cidrs] : []
+
+      content {
+        test     = "NotIpAddress"
+        variable = "aws:SourceIp"
+        values   = condition.value
+      }
+    }
+
+    dynamic "condition" {
+      for_each = length(var.allowed_source_vpce_ids) > 0 ? [var.allowed_source_vpce_ids] : []
+
+      content {
+        test     = "StringNotEqualsIfExists"
+        variable = "aws:SourceVpce"
+        values   = condition.value
+      }
+    }
+
+    dynamic "condition" {
+      for_each = length(var.allowed_source_vpc_ids) > 0 ? [var.allowed_source_vpc_ids] : []
+
+      content {
+        test     = "StringNotEqualsIfExists"
+        variable = "aws:SourceVpc"
+        values   = condition.value
+      }
+    }
+
+    # AWS service principals, such as `config.amazonaws.com`, calling with their own identity
+    condition {
+      test     = "Bool"
+      variable = "aws:PrincipalIsAWSService"
+      values   = ["false"]
+    }
+
+    condition {
+      test     = "Bool"
+      variable = "aws:ViaAWSService"
+      values   = ["false"]
+    }
+
+    dynamic "condition" {
+      for_each = length(local.allowed_sources_exempt_principal_arns) > 0 ? [local.allowed_sources_exempt_principal_arns] : []
+
+      content {
+        test     = "ArnNotLike"
+        variable = "aws:PrincipalArn"
+        values   = condition.value
+      }
+    }
+  }
+}
+
+# The final bucket policy, merged here so that it can be exposed as an output
+data "aws_iam_policy_document" "bucket_policy" {
+  count = local.enabled ? 1 : 0
+
+  source_policy_documents = local.source_policy_documents
+}
+
+# Checks on the inputs of the bucket, which is declared in `cloudposse/s3-bucket/aws` and cannot carry them itself
+resource "terraform_data" "config_bucket" {
+  count = local.enabled ? 1 : 0
+
+  lifecycle {
+    precondition {
+      condition     = length(var.required_cost_tags) > 0 || !contains(var.cost_tags_required_stages, module.this.stage != null ? module.this.stage : "")
+      error_message = "The required_cost_tags must not be empty in the stages listed in cost_tags_required_stages."
+    }
+
+    precondition {
+      condition = !var.enable_glacier_transition || alltrue([
+        for rule in values(local.lifecycle_rule_settings) : rule.glacier_transition_days > rule.standard_transition_days
+      ])
+      error_message = "The glacier_transition_days must be greater than standard_transition_days, also in each of prefix_lifecycle_rules."
+    }
+
+    precondition {
+      condition = alltrue([
+        for rule in values(local.lifecycle_rule_settings) :
+        rule.expiration_days > (var.enable_glacier_transition ? rule.glacier_transition_days : rule.standard_transition_days)
+      ])
+      error_message = "The expiration_days must be greater than glacier_transition_days (or standard_transition_days when the Glacier transition is disabled), also in each of prefix_lifecycle_rules."
+    }
+
+    precondition {
+      condition = !var.enable_glacier_transition || alltrue([
+        for rule in values(local.lifecycle_rule_settings) : rule.noncurrent_version_expiration_days > rule.noncurrent_version_transition_days
+      ])
+      error_message = "The noncurrent_version_expiration_days must be greater than noncurrent_version_transition_days, also in each of prefix_lifecycle_rules."
+    }
+
+    # S3 applies the earliest of overlapping expirations, so longer ones for a prefix would never take effect
+    precondition {
+      condition = !var.lifecycle_rule_enabled || alltrue([
+        for prefix in keys(var.prefix_lifecycle_rules) :
+        local.lifecycle_rule_settings[prefix].expiration_days <= var.expiration_days || !(startswith(prefix, var.lifecycle_prefix) || startswith(var.lifecycle_prefix, prefix))
+      ])
+      error_message = "The expiration_days of prefix_lifecycle_rules overlapping the bucket-wide rule must not exceed expiration_days. Set lifecycle_rule_enabled to false to extend the retention for a prefix."
+    }
+
+    precondition {
+      condition = !var.lifecycle_rule_enabled || alltrue([
+        for prefix in keys(var.prefix_lifecycle_rules) :
+        local.lifecycle_rule_settings[prefix].noncurrent_version_expiration_days <= var.noncurrent_version_expiration_days || !(startswith(prefix, var.lifecycle_prefix) || startswith(var.lifecycle_prefix, prefix))
+      ])
+      error_message = "The noncurrent_version_expiration_days of prefix_lifecycle_rules overlapping the bucket-wide rule must not exceed noncurrent_version_expiration_days. Set lifecycle_rule_enabled to false to extend the retention for a prefix."
+    }
+
+    precondition {
+      condition     = !var.s3_replication_enabled || var.s3_replica_bucket_arn != ""
+      error_message = "The s3_replica_bucket_arn must be set when s3_replication_enabled is true."
+    }
+
+    precondition {
+      condition     = !var.s3_replication_enabled || !local.sse_kms_enabled || var.s3_replica_kms_key_arn != null
+      error_message = "The s3_replica_kms_key_arn must be set when s3_replication_enabled is true and sse_algorithm is SSE-KMS."
+    }
+  }
+}
+
 module "config_bucket" {
-  source  = "cloudposse/config-storage/aws"
-  version = "1.0.2"
-
-  expiration_days                    = var.expiration_days
-  force_destroy                      = false
-  glacier_transition_days            = var.glacier_transition_days
-  lifecycle_rule_enabled             = var.lifecycle_rule_enabled
-  noncurrent_version_expiration_days = var.noncurrent_version_expiration_days
-  noncurrent_version_transition_days = var.noncurrent_version_transition_days
-  sse_algorithm                      = "AES256"
-  standard_transition_days           = var.standard_transition_days
-  access_log_bucket_name             = var.access_log_bucket_name
+  source  = "cloudposse/s3-bucket/aws"
+  version = "4.10.0"
+
+  acl                           = var.acl
+  bucket_key_enabled            = local.bucket_key_enabled
+  bucket_name                   = local.bucket_name
+  force_destroy                 = false
+  kms_master_key_arn            = var.kms_master_key_arn
+  lifecycle_configuration_rules = local.lifecycle_configuration_rules
+  logging                       = local.logging
+  s3_object_ownership           = var.s3_object_ownership
+  s3_replica_bucket_arn         = var.s3_replica_bucket_arn
+  s3_replication_enabled        = var.s3_replication_enabled
+  s3_replication_rules          = local.s3_replication_rules
+  source_policy_documents       = data.aws_iam_policy_document.bucket_policy[*].json
+  sse_algorithm                 = var.sse_algorithm
+  transfer_acceleration_enabled = var.transfer_acceleration_enabled
+  versioning_enabled            = true
+
+  # The TLS-only statement is part of `source_policy_documents` instead, see `data.aws_iam_policy_document.ssl_requests_only`
+  allow_ssl_requests_only = false
+
+  # The defaults of the `cloudposse/config-storage/aws` module this component used before,
+  # set explicitly where `cloudposse/s3-bucket/aws` defaults differ or may change
+  allow_encrypted_uploads_only = false
+  block_public_acls            = true
+  block_public_policy          = true
+  ignore_public_acls           = true
+  restrict_public_buckets      = true
+  user_enabled                 = false
+
+  # Merged with the tags from the context
+  tags = var.required_cost_tags
 
   context = module.this.context
 }
+
+# Adopts an existing bucket, e.g. one created by hand, instead of creating a new one.
+# The bucket's versioning, encryption, lifecycle and policy settings are then overwritten by this component.
+import {
+  for_each = local.enabled && var.existing_bucket_name != null ? toset([var.existing_bucket_name]) : toset([])
+
+  to = module.config_bucket.aws_s3_bucket.default[0]
+  id = each.value
+}
@@ -0,0 +1,80 @@
+locals {
+  enabled = module.this.enabled
+
+  # Config delivers to <bucket>/<prefix>/AWSLogs/<account-id>/Config/<region>/<yyyy>/<m>/<d>/ConfigSnapshot/
+  location = format("s3://%s/%sAWSLogs", var.bucket_id, var.s3_key_prefix != "" ? format("%s/", trim(var.s3_key_prefix, "/")) : "")
+}
+
+resource "aws_glue_catalog_database" "default" {
+  count = local.enabled ? 1 : 0
+
+  # Athena does not allow hyphens in database names
+  name        = replace(module.this.id, "-", "_")
+  description = "AWS Config configuration history delivered to ${var.bucket_id}"
+
+  tags = module.this.tags
+}
+
+# See https://docs.aws.amazon.com/config/latest/developerguide/query-snapshot.html
+resource "aws_glue_catalog_table" "config_snapshots" {
+  count = local.enabled ? 1 : 0
+
+  name          = "config_snapshots"
+  database_name = aws_glue_catalog_database.default[0].name
+  table_type    = "EXTERNAL_TABLE"
+
+  # Partition projection spares running crawlers or `MSCK REPAIR TABLE` as new snapshots arrive
+  parameters = {
+    "EXTERNAL"                    = "TRUE"
+    "projection.enabled"          = "true"
+    "projection.accountid.type"   = "injected"
+    "projection.region.type"      = "enum"
+    "projection.region.values"    = join(",", var.regions)
+    "projection.dt.type"          = "date"
+    "projection.dt.format"        = "yyyy/M/d"
+    "projection.dt.range"         = format("%s,NOW", var.projection_start_date)
+    "projection.dt.interval"      = "1"
+    "projection.dt.interval.unit" = "DAYS"
+    "storage.location.template"   = format("%s/$${accountid}/Config/$${region}/$${dt}/ConfigSnapshot", local.location)
+  }
+
+  partition_keys {
+    name = "accountid"
+    type = "string"
+  }
+
+  partition_keys {
+    name = "region"
+    type = "string"
+  }
+
+  partition_keys {
+    name = "dt"
+    type = "string"
+  }
+
+  storage_descriptor {
+    location      = local.location
+    input_format  = "org.apache.hadoop.mapred.TextInputFormat"
+    output_format = "org.apache.hadoop.hive.ql.io.HiveIgnoreKeyTextOutputFormat"
+
+    ser_de_info {
+      serialization_library = "org.openx.data.jsonserde.JsonSerDe"
+    }
+
+    columns {
+      name = "fileversion"
+      type = "string"
+    }
+
+    columns {
+      name = "configsnapshotid"
+      type = "string"
+    }
+
+    columns {
+      name = "configurationitems"
+      type = "array<struct<configurationitemversion:string,configurationitemcapturetime:string,configurationstateid:bigint,awsaccountid:string,configurationitemstatus:string,resourcetype:string,resourceid:string,resourcename:string,arn:string,awsregion:string,availabilityzone:string,configurationstatemd5hash:string,configuration:string,supplementaryconfiguration:map<string,string>,tags:map<string,string>,resourcecreationtime:string>>"
+    }
+  }
+}
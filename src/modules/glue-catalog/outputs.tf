@@ -0,0 +1,9 @@
+output "database_name" {
+  value       = one(aws_glue_catalog_database.default[*].name)
+  description = "Glue database name"
+}
+
+output "table_name" {
+  value       = one(aws_glue_catalog_table.config_snapshots[*].name)
+  description = "Glue table name for Config snapshots"
+}
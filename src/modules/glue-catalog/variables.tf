@@ -0,0 +1,21 @@
+variable "bucket_id" {
+  type        = string
+  description = "Name of the bucket AWS Config delivers to"
+}
+
+variable "s3_key_prefix" {
+  type        = string
+  description = "Key prefix AWS Config delivers under, if any"
+  default     = ""
+}
+
+variable "regions" {
+  type        = list(string)
+  description = "Regions whose Config snapshots are delivered to the bucket, used for partition projection"
+}
+
+variable "projection_start_date" {
+  type        = string
+  description = "Earliest snapshot date (`yyyy/M/d`) covered by partition projection"
+  default     = "2020/1/1"
+}
@@ -0,0 +1,43 @@
+locals {
+  multi_region_access_point_enabled = local.enabled && var.multi_region_access_point_enabled
+
+  multi_region_access_point_buckets = concat(
+    [module.config_bucket.bucket_id],
+    var.s3_replica_bucket_arn != "" ? [element(split(":::", var.s3_replica_bucket_arn), 1)] : [],
+  )
+}
+
+module "multi_region_access_point_label" {
+  source  = "cloudposse/label/null"
+  version = "0.25.0"
+
+  # Multi-Region Access Point names are limited to 50 characters
+  id_length_limit = 50
+  attributes      = ["mrap"]
+
+  context = module.this.context
+}
+
+# Puts the Config bucket, and the replication destination if any, behind a single global endpoint
+resource "aws_s3control_multi_region_access_point" "default" {
+  count = local.multi_region_access_point_enabled ? 1 : 0
+
+  details {
+    name = module.multi_region_access_point_label.id
+
+    public_access_block {
+      block_public_acls       = true
+      block_public_policy     = true
+      ignore_public_acls      = true
+      restrict_public_buckets = true
+    }
+
+    dynamic "region" {
+      for_each = local.multi_region_access_point_buckets
+
+      content {
+        bucket = region.value
+      }
+    }
+  }
+}
@@ -12,3 +12,85 @@ output "config_bucket_arn" {
   value       = module.config_bucket.bucket_arn
   description = "Config bucket ARN"
 }
+
+# Flat names for consumption by the `aws-config` component via `remote-state`
+
+output "bucket_id" {
+  value       = module.config_bucket.bucket_id
+  description = "Config bucket ID, for use as the delivery channel bucket"
+}
+
+output "bucket_arn" {
+  value       = module.config_bucket.bucket_arn
+  description = "Config bucket ARN"
+}
+
+output "kms_key_arn" {
+  value       = local.enabled && local.sse_kms_enabled && var.kms_master_key_arn != "" ? var.kms_master_key_arn : null
+  description = "ARN of the KMS key encrypting the Config bucket, or `null` when SSE-KMS is not used"
+}
+
+output "config_delivery_s3_key_prefix" {
+  value       = var.config_delivery_s3_key_prefix != "" ? trim(var.config_delivery_s3_key_prefix, "/") : null
+  description = "Key prefix for the AWS Config delivery channel, or `null` to deliver at the bucket root"
+}
+
+output "storage_lens_configuration_arn" {
+  value       = one(aws_s3control_storage_lens_configuration.default[*].arn)
+  description = "Storage Lens configuration ARN"
+}
+
+output "access_point_arn" {
+  value       = one(aws_s3_access_point.default[*].arn)
+  description = "S3 Access Point ARN"
+}
+
+output "access_point_alias" {
+  value       = one(aws_s3_access_point.default[*].alias)
+  description = "S3 Access Point alias"
+}
+
+output "config_bucket_policy" {
+  value       = one(data.aws_iam_policy_document.bucket_policy[*].json)
+  description = "Config bucket policy JSON, as applied to the bucket"
+}
+
+output "cloudtrail_arn" {
+  value       = one(aws_cloudtrail.data_events[*].arn)
+  description = "ARN of the CloudTrail trail recording data events on the Config bucket"
+}
+
+output "macie_classification_job_id" {
+  value       = one(aws_macie2_classification_job.default[*].job_id)
+  description = "ID of the Macie classification job for the Config bucket"
+}
+
+output "replication_role_arn" {
+  value       = module.config_bucket.replication_role_arn
+  description = "ARN of the IAM role used for replication"
+}
+
+output "glue_database_name" {
+  value       = module.glue_catalog.database_name
+  description = "Glue database name for querying Config snapshots with Athena"
+}
+
+output "glue_table_name" {
+  value       = module.glue_catalog.table_name
+  description = "Glue table name for querying Config snapshots with Athena"
+}
+
+output "sns_topic_arn" {
+  value       = local.sns_topic_arn
+  description = "ARN of the SNS topic for AWS Config delivery channel notifications"
+}
+
+output "kms_key_policy" {
+  value       = one(data.aws_iam_policy_document.kms_key[*].json)
+  description = "KMS key policy statements allowing AWS Config to use the bucket's customer managed key, to be merged into the key policy"
+}
+
+output "multi_region_access_point_alias" {
+  value       = one(aws_s3control_multi_region_access_point.default[*].alias)
+  description = "S3 Multi-Region Access Point alias"
+}
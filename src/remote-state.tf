@@ -0,0 +1,13 @@
+module "logs_bucket" {
+  source  = "cloudposse/stack-config/yaml//modules/remote-state"
+  version = "1.8.0"
+
+  component = var.logs_bucket_component_name
+  bypass    = !local.enabled || var.logs_bucket_component_name == ""
+
+  defaults = {
+    bucket_id = var.access_log_bucket_name
+  }
+
+  context = module.this.context
+}
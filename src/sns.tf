@@ -0,0 +1,43 @@
+locals {
+  sns_topic_enabled = local.enabled && var.sns_topic_enabled
+  sns_topic_arn     = local.sns_topic_enabled ? one(aws_sns_topic.default[*].arn) : var.sns_topic_arn
+}
+
+# Notification topic for the AWS Config delivery channel
+resource "aws_sns_topic" "default" {
+  count = local.sns_topic_enabled ? 1 : 0
+
+  name = module.this.id
+
+  tags = module.this.tags
+}
+
+# See https://docs.aws.amazon.com/config/latest/developerguide/sns-topic-policy.html
+data "aws_iam_policy_document" "sns_topic" {
+  count = local.sns_topic_enabled ? 1 : 0
+
+  statement {
+    sid       = "AWSConfigSNSPolicy"
+    effect    = "Allow"
+    actions   = ["SNS:Publish"]
+    resources = [aws_sns_topic.default[0].arn]
+
+    principals {
+      type        = "Service"
+      identifiers = ["config.amazonaws.com"]
+    }
+
+    condition {
+      test     = "StringEquals"
+      variable = var.organization_id != null ? "aws:SourceOrgID" : "aws:SourceAccount"
+      values   = var.organization_id != null ? [var.organization_id] : local.config_delivery_account_ids
+    }
+  }
+}
+
+resource "aws_sns_topic_policy" "default" {
+  count = local.sns_topic_enabled ? 1 : 0
+
+  arn    = aws_sns_topic.default[0].arn
+  policy = data.aws_iam_policy_document.sns_topic[0].json
+}
@@ -0,0 +1,24 @@
+locals {
+  storage_lens_enabled = local.enabled && var.storage_lens_enabled
+}
+
+# Tracks the storage growth of Config data using the free Storage Lens metrics, scoped to this bucket only.
+resource "aws_s3control_storage_lens_configuration" "default" {
+  count = local.storage_lens_enabled ? 1 : 0
+
+  config_id = local.bucket_name
+
+  storage_lens_configuration {
+    enabled = true
+
+    account_level {
+      bucket_level {}
+    }
+
+    include {
+      buckets = [module.config_bucket.bucket_arn]
+    }
+  }
+
+  tags = module.this.tags
+}
@@ -0,0 +1,173 @@
+# Plan-only checks of the input preconditions, against mocked providers so that no AWS credentials are needed.
+# Run `terraform init && terraform test` in `src/` with `account-map` vendored next to the component.
+
+mock_provider "aws" {
+  mock_data "aws_partition" {
+    defaults = {
+      partition = "aws"
+    }
+  }
+
+  mock_data "aws_caller_identity" {
+    defaults = {
+      account_id = "111111111111"
+      arn        = "arn:aws:iam::111111111111:user/terraform"
+    }
+  }
+
+  mock_data "aws_iam_policy_document" {
+    defaults = {
+      json = "{}"
+    }
+  }
+}
+
+override_module {
+  target = module.iam_roles
+  outputs = {
+    terraform_role_arn     = null
+    terraform_profile_name = null
+  }
+}
+
+variables {
+  region    = "us-east-1"
+  namespace = "eg"
+  stage     = "test"
+  name      = "config"
+}
+
+run "defaults_are_valid" {
+  command = plan
+}
+
+run "glacier_transition_before_standard_transition" {
+  command = plan
+
+  variables {
+    standard_transition_days = 60
+    glacier_transition_days  = 30
+  }
+
+  expect_failures = [terraform_data.config_bucket]
+}
+
+run "expiration_before_glacier_transition" {
+  command = plan
+
+  variables {
+    glacier_transition_days = 60
+    expiration_days         = 60
+  }
+
+  expect_failures = [terraform_data.config_bucket]
+}
+
+run "noncurrent_expiration_before_noncurrent_transition" {
+  command = plan
+
+  variables {
+    noncurrent_version_transition_days = 30
+    noncurrent_version_expiration_days = 20
+  }
+
+  expect_failures = [terraform_data.config_bucket]
+}
+
+run "newer_noncurrent_versions_out_of_range" {
+  command = plan
+
+  variables {
+    newer_noncurrent_versions = 101
+  }
+
+  expect_failures = [var.newer_noncurrent_versions]
+}
+
+run "prefix_rule_with_invalid_day_ordering" {
+  command = plan
+
+  variables {
+    prefix_lifecycle_rules = {
+      "AWSLogs/222222222222/" = {
+        glacier_transition_days = 20
+      }
+    }
+  }
+
+  expect_failures = [terraform_data.config_bucket]
+}
+
+run "prefix_rule_expiring_after_bucket_wide_rule" {
+  command = plan
+
+  variables {
+    prefix_lifecycle_rules = {
+      "AWSLogs/222222222222/" = {
+        expiration_days = 365
+      }
+    }
+  }
+
+  expect_failures = [terraform_data.config_bucket]
+}
+
+run "prefix_rule_expiring_noncurrent_versions_after_bucket_wide_rule" {
+  command = plan
+
+  variables {
+    prefix_lifecycle_rules = {
+      "AWSLogs/222222222222/" = {
+        noncurrent_version_expiration_days = 365
+      }
+    }
+  }
+
+  expect_failures = [terraform_data.config_bucket]
+}
+
+run "prefix_rule_expiring_later_with_bucket_wide_rule_disabled" {
+  command = plan
+
+  variables {
+    lifecycle_rule_enabled = false
+    prefix_lifecycle_rules = {
+      "AWSLogs/222222222222/" = {
+        expiration_days = 365
+      }
+    }
+  }
+}
+
+run "replication_without_replica_bucket" {
+  command = plan
+
+  variables {
+    s3_replication_enabled = true
+  }
+
+  expect_failures = [terraform_data.config_bucket]
+}
+
+run "replication_with_sse_kms_without_replica_key" {
+  command = plan
+
+  variables {
+    s3_replication_enabled = true
+    s3_replica_bucket_arn  = "arn:aws:s3:::eg-test-config-replica"
+    sse_algorithm          = "aws:kms"
+  }
+
+  expect_failures = [terraform_data.config_bucket]
+}
+
+run "cost_tags_missing_in_required_stage" {
+  command = plan
+
+  variables {
+    stage                     = "prod"
+    cost_tags_required_stages = ["prod"]
+  }
+
+  expect_failures = [terraform_data.config_bucket]
+}
@@ -9,12 +9,32 @@ variable "lifecycle_rule_enabled" {
   default     = true
 }
 
+variable "lifecycle_prefix" {
+  type        = string
+  description = <<-EOT
+    Prefix filter for the bucket-wide lifecycle rule, e.g. `AWSLogs/` to only apply transitions and expiration to
+    Config delivery paths. Leave empty to apply the rule to the whole bucket.
+    EOT
+  default     = ""
+}
+
 variable "noncurrent_version_expiration_days" {
   type        = number
   default     = 90
   description = "Specifies when noncurrent object versions expire"
 }
 
+variable "newer_noncurrent_versions" {
+  type        = number
+  default     = null
+  description = "Number of noncurrent versions to retain regardless of `noncurrent_version_expiration_days`. Set to `null` to expire all noncurrent versions by age"
+
+  validation {
+    condition     = var.newer_noncurrent_versions == null || (var.newer_noncurrent_versions >= 1 && var.newer_noncurrent_versions <= 100)
+    error_message = "The newer_noncurrent_versions must be between 1 and 100, or null."
+  }
+}
+
 variable "noncurrent_version_transition_days" {
   type        = number
   default     = 30
@@ -51,8 +71,381 @@ variable "access_log_bucket_name" {
   description = "Name of the S3 bucket where s3 access log will be sent to"
 }
 
+variable "logs_bucket_component_name" {
+  type        = string
+  default     = ""
+  description = <<-EOT
+    Name of the component providing the S3 bucket where s3 access log will be sent to, resolved via remote state.
+    When set, it takes precedence over `access_log_bucket_name`.
+    EOT
+}
+
 variable "acl" {
   type        = string
   description = "The canned ACL to apply. We recommend log-delivery-write for compatibility with AWS services"
   default     = "log-delivery-write"
 }
+
+variable "s3_object_ownership" {
+  type        = string
+  description = <<-EOT
+    Specifies the S3 object ownership control.
+    Valid values are `ObjectWriter`, `BucketOwnerPreferred`, and `BucketOwnerEnforced`.
+    `BucketOwnerEnforced` disables ACLs entirely, in which case `acl` is ignored.
+    EOT
+  default     = "BucketOwnerPreferred"
+
+  validation {
+    condition     = contains(["ObjectWriter", "BucketOwnerPreferred", "BucketOwnerEnforced"], var.s3_object_ownership)
+    error_message = "The s3_object_ownership must be one of `ObjectWriter`, `BucketOwnerPreferred`, or `BucketOwnerEnforced`."
+  }
+}
+
+variable "bucket_name_override" {
+  type        = string
+  description = <<-EOT
+    Exact name to give the bucket, for organizations whose naming standards do not follow null-label conventions.
+    When set, the name derived from the label context is ignored.
+    EOT
+  default     = null
+}
+
+variable "existing_bucket_name" {
+  type        = string
+  description = <<-EOT
+    Name of an existing bucket to adopt, e.g. when migrating from a hand-rolled Config bucket.
+    The bucket is imported into the Terraform state instead of being created, and takes precedence over `bucket_name_override`.
+    Keep it set after the import, unless the name derived from the context or `bucket_name_override` matches the bucket.
+    EOT
+  default     = null
+}
+
+variable "storage_lens_enabled" {
+  type        = bool
+  description = "Set to true to create an S3 Storage Lens configuration scoped to the Config bucket to track its storage growth"
+  default     = false
+}
+
+variable "access_point_enabled" {
+  type        = bool
+  description = "Set to true to create an S3 Access Point granting read-only access to the Config bucket"
+  default     = false
+}
+
+variable "access_point_principal_arns" {
+  type        = list(string)
+  description = <<-EOT
+    ARNs of the IAM principals allowed to read through the S3 Access Point. Required if `access_point_enabled` is `true`.
+    The principals still need identity-based permissions for `s3:GetObject` and `s3:ListBucket`.
+    EOT
+  default     = []
+}
+
+variable "sse_algorithm" {
+  type        = string
+  description = <<-EOT
+    The server-side encryption algorithm to use. Valid values are `AES256`, `aws:kms`, and `aws:kms:dsse`.
+    Use `aws:kms:dsse` (dual-layer SSE-KMS) where double encryption is mandated.
+    EOT
+  default     = "AES256"
+
+  validation {
+    condition     = contains(["AES256", "aws:kms", "aws:kms:dsse"], var.sse_algorithm)
+    error_message = "The sse_algorithm must be one of `AES256`, `aws:kms`, or `aws:kms:dsse`."
+  }
+}
+
+variable "kms_master_key_arn" {
+  type        = string
+  description = "The AWS KMS master key ARN used for the `SSE-KMS` encryption. Used only when `sse_algorithm` is `aws:kms` or `aws:kms:dsse`"
+  default     = ""
+}
+
+variable "bucket_key_enabled" {
+  type        = bool
+  description = <<-EOT
+    Set to true to use Amazon S3 Bucket Keys for SSE-KMS, which reduce the cost of AWS KMS requests.
+    Config delivers many small objects, so per-object KMS calls quickly become expensive.
+    Has no effect unless `sse_algorithm` is `aws:kms`, since S3 Bucket Keys are not supported with DSSE-KMS.
+    EOT
+  default     = true
+}
+
+variable "prefix_lifecycle_rules" {
+  type = map(object({
+    standard_transition_days           = optional(number)
+    glacier_transition_days            = optional(number)
+    expiration_days                    = optional(number)
+    noncurrent_version_transition_days = optional(number)
+    noncurrent_version_expiration_days = optional(number)
+  }))
+  description = <<-EOT
+    Additional lifecycle rules keyed by object key prefix, e.g. `AWSLogs/111111111111/` to give a production account's
+    Config data a different retention in a centralized bucket. Omitted values default to the bucket-wide settings.
+    When rules overlap, S3 applies the earliest expiration, so while `lifecycle_rule_enabled` is `true` a prefix rule can
+    only shorten the retention of current and noncurrent versions. To extend the retention for a prefix, set `lifecycle_rule_enabled` to `false` and define
+    a rule for every prefix instead.
+    EOT
+  default     = {}
+}
+
+variable "partition" {
+  type        = string
+  description = <<-EOT
+    The AWS partition (`aws`, `aws-us-gov` or `aws-cn`) used to build ARNs in the bucket policy.
+    Defaults to the partition of the provider's region. Mostly useful for test fixtures.
+    EOT
+  default     = null
+}
+
+variable "aggregator_account_id" {
+  type        = string
+  description = "ID of an AWS Config aggregator or security-tooling account granted read-only access (`s3:GetObject`, `s3:ListBucket`) to the bucket"
+  default     = null
+}
+
+variable "aggregator_role_arns" {
+  type        = list(string)
+  description = "ARNs of IAM roles, e.g. in the aggregator account, granted read-only access (`s3:GetObject`, `s3:ListBucket`) to the bucket"
+  default     = []
+}
+
+variable "privileged_principal_arns" {
+  type        = list(map(list(string)))
+  description = <<-EOT
+    List of maps. Each map has a key, an IAM Principal ARN, whose associated value is
+    a list of S3 path prefixes to grant `privileged_principal_actions` permissions for that principal,
+    in addition to the bucket itself, which is automatically included. Prefixes should not begin with '/'.
+    An empty list of prefixes grants access to the whole bucket.
+    Useful for granting incident-response roles narrowly scoped read access.
+    EOT
+  default     = []
+}
+
+variable "privileged_principal_actions" {
+  type        = list(string)
+  description = "List of actions to permit `privileged_principal_arns` to perform on the bucket and the allowed prefixes"
+  default     = ["s3:GetObject", "s3:ListBucket", "s3:GetBucketLocation"]
+}
+
+variable "cloudtrail_data_events_enabled" {
+  type        = bool
+  description = "Set to true to record S3 data events (object-level reads and deletes) on the Config bucket with a dedicated CloudTrail trail"
+  default     = false
+}
+
+variable "cloudtrail_bucket_name" {
+  type        = string
+  description = "Name of the existing CloudTrail bucket the data events trail delivers to. Required if `cloudtrail_data_events_enabled` is `true`"
+  default     = null
+}
+
+variable "macie_classification_job_enabled" {
+  type        = bool
+  description = "Set to true to create an Amazon Macie sensitive data discovery job for the Config bucket. Macie must already be enabled in the account"
+  default     = false
+}
+
+variable "macie_classification_job_frequency" {
+  type        = string
+  description = "How often the Macie classification job runs. Valid values are `DAILY`, `WEEKLY` (on Mondays), and `MONTHLY` (on the first day of the month)"
+  default     = "WEEKLY"
+
+  validation {
+    condition     = contains(["DAILY", "WEEKLY", "MONTHLY"], var.macie_classification_job_frequency)
+    error_message = "The macie_classification_job_frequency must be one of `DAILY`, `WEEKLY`, or `MONTHLY`."
+  }
+}
+
+variable "read_only_role_arns" {
+  type        = list(string)
+  description = "ARNs of IAM roles, e.g. auditors, granted read-only access (`s3:GetObject`, `s3:ListBucket`) to the whole bucket"
+  default     = []
+}
+
+variable "deny_object_deletion_enabled" {
+  type        = bool
+  description = <<-EOT
+    Set to true to deny `s3:DeleteObject` and `s3:DeleteObjectVersion` to all principals, including administrators,
+    except `break_glass_role_arns`. Objects are still removed by lifecycle expiration. Changing the bucket policy,
+    lifecycle or versioning is also denied, except to `break_glass_role_arns` and the principal applying this component.
+    The account root user can still delete the bucket policy, so use S3 Object Lock where Config history must be immutable.
+    EOT
+  default     = false
+}
+
+variable "break_glass_role_arns" {
+  type        = list(string)
+  description = "ARNs of IAM roles exempt from the deny on object deletion and on bucket policy, lifecycle and versioning changes when `deny_object_deletion_enabled` is `true`"
+  default     = []
+}
+
+variable "s3_replication_enabled" {
+  type        = bool
+  description = "Set to true to replicate the Config bucket to `s3_replica_bucket_arn`"
+  default     = false
+}
+
+variable "s3_replica_bucket_arn" {
+  type        = string
+  description = "ARN of the destination bucket for replication. The bucket must exist and have versioning enabled"
+  default     = ""
+}
+
+variable "s3_replica_kms_key_arn" {
+  type        = string
+  description = <<-EOT
+    ARN of the KMS key replicas are encrypted with in the destination bucket. Required if `sse_algorithm` is SSE-KMS,
+    because S3 only replicates SSE-KMS encrypted objects with a replica key. The key policies of both keys must allow
+    the role in the `replication_role_arn` output.
+    EOT
+  default     = null
+}
+
+variable "replication_time_control_enabled" {
+  type        = bool
+  description = "Set to true to enable S3 Replication Time Control (15-minute replication SLA). Also enables replication metrics"
+  default     = false
+}
+
+variable "replication_metrics_enabled" {
+  type        = bool
+  description = "Set to true to enable S3 replication metrics"
+  default     = false
+}
+
+variable "glue_catalog_enabled" {
+  type        = bool
+  description = "Set to true to create a Glue database and table (with partition projection) over the delivered Config snapshots, for querying with Athena"
+  default     = false
+}
+
+variable "glue_catalog_regions" {
+  type        = list(string)
+  description = "Regions whose Config snapshots are delivered to the bucket, used for partition projection. Defaults to `region`"
+  default     = []
+}
+
+variable "config_delivery_s3_key_prefix" {
+  type        = string
+  description = <<-EOT
+    Key prefix the AWS Config delivery channel writes under. When set, the bucket policy only allows Config to
+    deliver below `<prefix>/AWSLogs/`. Pass the `config_delivery_s3_key_prefix` output to the delivery channel.
+    EOT
+  default     = ""
+}
+
+variable "conformance_pack_access_enabled" {
+  type        = bool
+  description = <<-EOT
+    Set to true to allow AWS Config conformance packs to deliver to the bucket, under `config_delivery_s3_key_prefix`.
+    Access is granted to the conformance packs service-linked role of the accounts in `organization_id`,
+    or of the current account if `organization_id` is not set.
+    With `organization_id` set, requires a bucket name starting with `awsconfigconforms`, e.g. through `bucket_name_override`.
+    EOT
+  default     = false
+}
+
+variable "organization_id" {
+  type        = string
+  description = "ID of the AWS Organization (`o-xxxxxxxxxx`) whose accounts deliver to the bucket"
+  default     = null
+}
+
+variable "transfer_acceleration_enabled" {
+  type        = bool
+  description = "Set to true to enable S3 Transfer Acceleration, e.g. for accounts pulling Config data across continents"
+  default     = false
+}
+
+variable "allowed_source_cidrs" {
+  type        = list(string)
+  description = <<-EOT
+    If not empty, deny all access to the bucket from source IPs outside these CIDR blocks, unless allowed by
+    `allowed_source_vpce_ids` or `allowed_source_vpc_ids`. Requests through a VPC endpoint have no source IP and are
+    denied unless their endpoint or VPC is allowed.
+    Exempt are AWS service principals (such as `config.amazonaws.com`) calling with their own identity, requests made by
+    AWS services on a principal's behalf, and the principals in `allowed_source_exempt_principal_arns`.
+    Roles that AWS services assume are not exempt by themselves; the Terraform role and the replication and Macie roles
+    of this component are added to the exemptions automatically.
+    EOT
+  default     = []
+}
+
+variable "allowed_source_vpce_ids" {
+  type        = list(string)
+  description = "If not empty, deny all access to the bucket except through these VPC endpoints, or from the allowed CIDR blocks or VPCs"
+  default     = []
+}
+
+variable "allowed_source_vpc_ids" {
+  type        = list(string)
+  description = "If not empty, deny all access to the bucket except from these VPCs, or from the allowed CIDR blocks or VPC endpoints"
+  default     = []
+}
+
+variable "allowed_source_exempt_principal_arns" {
+  type        = list(string)
+  description = <<-EOT
+    IAM principal ARNs, wildcards allowed, exempt from the `allowed_source_*` network restrictions,
+    e.g. roles assumed by AWS services or break-glass roles
+    EOT
+  default     = []
+}
+
+variable "sns_topic_enabled" {
+  type        = bool
+  description = "Set to true to create an SNS topic, with the policy AWS Config needs, for delivery channel notifications"
+  default     = false
+}
+
+variable "sns_topic_arn" {
+  type        = string
+  description = "ARN of an existing SNS topic for delivery channel notifications, passed through to the `sns_topic_arn` output. Ignored if `sns_topic_enabled` is `true`"
+  default     = null
+}
+
+variable "config_delivery_account_ids" {
+  type        = list(string)
+  description = <<-EOT
+    IDs of the accounts whose AWS Config delivers to the bucket. If set, only these accounts are allowed to deliver by the
+    bucket policy. If neither this nor `organization_id` is set, AWS Config in any account may deliver to the bucket.
+    Also the accounts allowed to use the KMS key in the `kms_key_policy` output and to publish to the SNS topic,
+    defaulting to the current account.
+    Ignored if `organization_id` is set, in which case the whole organization is allowed.
+    EOT
+  default     = []
+}
+
+variable "tag_lifecycle_rules" {
+  type = list(object({
+    id                                 = string
+    tags                               = map(string)
+    expiration_days                    = number
+    noncurrent_version_expiration_days = optional(number)
+  }))
+  description = <<-EOT
+    Additional lifecycle rules expiring objects that carry all of the given tags, e.g. to expire
+    `oversized = "true"` Config items sooner. `noncurrent_version_expiration_days` defaults to `expiration_days`.
+    The rules are scoped to `lifecycle_prefix`, if set.
+    EOT
+  default     = []
+}
+
+variable "multi_region_access_point_enabled" {
+  type        = bool
+  description = "Set to true to register the Config bucket, and the replication destination `s3_replica_bucket_arn` if set, behind an S3 Multi-Region Access Point"
+  default     = false
+}
+
+variable "required_cost_tags" {
+  type        = map(string)
+  description = "Billing and cost-allocation tags added to the bucket, e.g. `{ CostCenter = \"security\" }`. Required in `cost_tags_required_stages`"
+  default     = {}
+}
+
+variable "cost_tags_required_stages" {
+  type        = list(string)
+  description = "Stages, e.g. `[\"prod\"]`, in which `required_cost_tags` must be set, otherwise the plan fails"
+  default     = []
+}